@@ -6,34 +6,60 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/adambkaplan/openshift-template-monitor/pkg/projects"
 	"github.com/adambkaplan/openshift-template-monitor/pkg/templates"
 	"github.com/golang/glog"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	defaultPort        = ":8080"
-	defaultKeepObjects = false
-	defaultInterval    = 300
-	defaultTimeout     = 60
+	defaultPort            = ":8080"
+	defaultKeepObjects     = false
+	defaultInterval        = 300 * time.Second
+	defaultTimeout         = 60 * time.Second
+	defaultTests           = "templates,projects"
+	defaultTemplatesDir    = ""
+	defaultManifestsDir    = ""
+	defaultWait            = true
+	defaultWaitTimeout     = 120
+	defaultTeardownTimeout = 60
+	defaultHooksDir        = ""
+	defaultWebhookURL      = ""
 )
 
 var (
-	addr           string
-	keepObjects    bool
-	interval       int
-	timeout        int
-	metricsHandler http.Handler
+	addr            string
+	keepObjects     bool
+	interval        time.Duration
+	timeout         time.Duration
+	testsFlag       string
+	templatesDir    string
+	manifestsDir    string
+	wait            bool
+	waitTimeout     int
+	teardownTimeout int
+	hooksDir        string
+	webhookURL      string
+	metricsHandler  http.Handler
 )
 
 func init() {
 	flag.StringVar(&addr, "listen-address", defaultPort, "The address to listen on for HTTP requests.")
 	flag.BoolVar(&keepObjects, "keep-objects", defaultKeepObjects, "Keep objects created by the smoketest")
-	flag.IntVar(&interval, "interval", defaultInterval, "Interval to run the smoketest job (seconds)")
-	flag.IntVar(&timeout, "timeout", defaultTimeout, "Timeout for launching a Template Instance (seconds)")
+	flag.DurationVar(&interval, "interval", defaultInterval, "Interval to run the smoketest job, e.g. 5m, 90s")
+	flag.DurationVar(&timeout, "timeout", defaultTimeout, "Timeout for launching a Template Instance, e.g. 5m, 90s")
+	flag.StringVar(&testsFlag, "tests", defaultTests, "Comma-separated list of smoketests to run (templates,projects)")
+	flag.StringVar(&templatesDir, "templates-dir", defaultTemplatesDir, "Directory of template smoketest specs. Loaded from the smoketest-templates ConfigMap if unset.")
+	flag.StringVar(&manifestsDir, "manifests-dir", defaultManifestsDir, "Directory Template manifests are mounted at, used to resolve a spec's manifestPath when specs are loaded from the smoketest-templates ConfigMap. Ignored when -templates-dir is set.")
+	flag.BoolVar(&wait, "wait", defaultWait, "Wait for every child object of a launched Template Instance to become individually ready")
+	flag.IntVar(&waitTimeout, "wait-timeout", defaultWaitTimeout, "Timeout for child objects of a Template Instance to become ready (seconds)")
+	flag.IntVar(&teardownTimeout, "teardown-timeout", defaultTeardownTimeout, "Timeout for a deleted Template Instance and its child objects to be fully removed (seconds)")
+	flag.StringVar(&hooksDir, "hooks-dir", defaultHooksDir, "Directory of Go plugins (*.so) exporting additional template smoketest hooks")
+	flag.StringVar(&webhookURL, "webhook-url", defaultWebhookURL, "URL to POST a JSON run summary to after each template smoketest run's teardown. Not sent with -keep-objects, or when the Template could not be created.")
 	flag.Parse()
 }
 
@@ -41,41 +67,113 @@ func main() {
 	exit := make(chan os.Signal, 1)
 	signal.Notify(exit, syscall.SIGINT, syscall.SIGTERM)
 
+	tests := parseTests(testsFlag)
+
 	glog.V(0).Info("Started template smoketest application")
 	glog.V(2).Infof("Listening at address %s", addr)
 	glog.V(2).Infof("Keeping test artifact objects: %t", keepObjects)
-	glog.V(2).Infof("Test interval: %d", interval)
-	glog.V(2).Infof("Instance launch timeout: %d", timeout)
+	glog.V(2).Infof("Test interval: %s", interval)
+	glog.V(2).Infof("Instance launch timeout: %s", timeout)
+	glog.V(2).Infof("Enabled smoketests: %s", testsFlag)
 
 	metricsHandler = prometheus.Handler()
 	http.HandleFunc("/healthz", handleHealthz)
 	http.HandleFunc("/metrics", handleMetrics)
 
-	templateTestGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "template_test_last_ran",
-			Help: "Time that the template smoketest last ran",
-		},
-		[]string{"result", "reason"},
-	)
-	templateLaunchGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "template_test_launch_duration_seconds",
-			Help: "Duration the cluster last took to launch a test template instance.",
-		},
-		[]string{"result", "reason"},
-	)
-	totalDurationGauge := prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "template_test_total_duration_seconds",
-			Help: "Total duration of the previous test.",
-		},
-		[]string{"result", "reason"},
-	)
-	prometheus.MustRegister(templateTestGauge, templateLaunchGauge, totalDurationGauge)
+	templates.SetWebhookURL(webhookURL)
+	if err := templates.LoadHookPlugins(hooksDir); err != nil {
+		glog.Errorf("Failed to load template smoketest hook plugins from %s: %v", hooksDir, err)
+	}
+
+	if tests["templates"] {
+		templateTestGauge := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "template_test_last_ran",
+				Help: "Time that the template smoketest last ran",
+			},
+			[]string{"template", "result", "reason"},
+		)
+		templateLaunchHistogram := prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "template_test_launch_duration_seconds",
+				Help:    "Duration the cluster last took to launch a test template instance.",
+				Buckets: []float64{.5, 1, 2, 5, 10, 30, 60, 120, 300},
+			},
+			[]string{"template", "result", "reason"},
+		)
+		totalDurationHistogram := prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "template_test_total_duration_seconds",
+				Help:    "Total duration of the previous test.",
+				Buckets: []float64{.5, 1, 2, 5, 10, 30, 60, 120, 300},
+			},
+			[]string{"template", "result", "reason"},
+		)
+		readyDurationHistogram := prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "template_test_ready_duration_seconds",
+				Help:    "Duration it took a Template Instance child object to become ready, by kind.",
+				Buckets: []float64{1, 2, 5, 10, 30, 60, 120, 300},
+			},
+			[]string{"template", "kind"},
+		)
+		phaseDurationHistogram := prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "template_test_phase_duration_seconds",
+				Help:    "Duration of a single named phase of the template smoketest pipeline.",
+				Buckets: []float64{.5, 1, 2, 5, 10, 30, 60, 120, 300},
+			},
+			[]string{"template", "phase"},
+		)
+		teardownDurationGauge := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "template_test_teardown_duration_seconds",
+				Help: "Duration the cluster last took to fully tear down a test template instance.",
+			},
+			[]string{"template", "result", "reason"},
+		)
+		hookGauge := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "hook_last_ran",
+				Help: "Time that a template smoketest hook last ran",
+			},
+			[]string{"name", "phase", "result"},
+		)
+		prometheus.MustRegister(templateTestGauge, templateLaunchHistogram, totalDurationHistogram, readyDurationHistogram, phaseDurationHistogram, teardownDurationGauge, hookGauge)
+		go schedule(interval, func() {
+			doTemplateSmoketest(templateTestGauge, templateLaunchHistogram, totalDurationHistogram, readyDurationHistogram, phaseDurationHistogram, teardownDurationGauge, hookGauge)
+		})
+	}
+
+	if tests["projects"] {
+		projectTestGauge := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "project_test_last_ran",
+				Help: "Time that the project smoketest last ran",
+			},
+			[]string{"result", "reason"},
+		)
+		projectProvisionGauge := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "project_test_provision_duration_seconds",
+				Help: "Duration the cluster last took to provision a test project.",
+			},
+			[]string{"result", "reason"},
+		)
+		projectTeardownGauge := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "project_test_teardown_duration_seconds",
+				Help: "Duration the cluster last took to tear down a test project.",
+			},
+			[]string{"result", "reason"},
+		)
+		prometheus.MustRegister(projectTestGauge, projectProvisionGauge, projectTeardownGauge)
+		go schedule(interval, func() {
+			doProjectSmoketest(projectTestGauge, projectProvisionGauge, projectTeardownGauge)
+		})
+	}
 
 	go http.ListenAndServe(addr, nil)
-	go runTemplateSmoketest(time.Duration(interval)*time.Second, templateTestGauge, templateLaunchGauge, totalDurationGauge)
 
 	<-exit
 	glog.V(0).Info("Exiting template smoketest application")
@@ -92,8 +190,20 @@ func handleMetrics(w http.ResponseWriter, r *http.Request) {
 	glog.V(1).Info("GET /metrics")
 }
 
-func runTemplateSmoketest(interval time.Duration, testGauge, launchGauge, durationGauge *prometheus.GaugeVec) {
-	glog.V(0).Info("Running template controller smoketests")
+// parseTests splits a comma-separated `-tests` flag value into a set of enabled test names.
+func parseTests(value string) map[string]bool {
+	enabled := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			enabled[name] = true
+		}
+	}
+	return enabled
+}
+
+// schedule runs fn immediately, then again every interval, until the process exits.
+func schedule(interval time.Duration, fn func()) {
 	first := true
 	for {
 		if !first {
@@ -101,26 +211,74 @@ func runTemplateSmoketest(interval time.Duration, testGauge, launchGauge, durati
 		} else {
 			first = false
 		}
-		doSmoketest(testGauge, launchGauge, durationGauge)
+		fn()
+	}
+}
+
+func doTemplateSmoketest(testGauge *prometheus.GaugeVec, launchHistogram, durationHistogram, readyDurationHistogram, phaseDurationHistogram *prometheus.HistogramVec, teardownGauge, hookGauge *prometheus.GaugeVec) {
+	test, err := templates.NewSmoketest(templatesDir, manifestsDir)
+	if err != nil {
+		glog.Errorf("Failed initiating template smoketest: %s", err)
+		publishTemplateResult(testGauge, launchHistogram, durationHistogram, readyDurationHistogram, phaseDurationHistogram, teardownGauge, hookGauge, templates.Result{Err: err})
+		return
+	}
+	opts := templates.RunOptions{
+		KeepObjects:     keepObjects,
+		Timeout:         timeout,
+		Wait:            wait,
+		WaitTimeout:     waitTimeout,
+		TeardownTimeout: teardownTimeout,
+	}
+	results, err := test.Run(opts)
+	if err != nil {
+		glog.Errorf("Failed running template smoketest: %s", err)
+		publishTemplateResult(testGauge, launchHistogram, durationHistogram, readyDurationHistogram, phaseDurationHistogram, teardownGauge, hookGauge, templates.Result{Err: err})
+		return
+	}
+	for _, result := range results {
+		publishTemplateResult(testGauge, launchHistogram, durationHistogram, readyDurationHistogram, phaseDurationHistogram, teardownGauge, hookGauge, result)
+	}
+}
+
+func publishTemplateResult(testGauge *prometheus.GaugeVec, launchHistogram, durationHistogram, readyDurationHistogram, phaseDurationHistogram *prometheus.HistogramVec, teardownGauge, hookGauge *prometheus.GaugeVec, result templates.Result) {
+	outcome := "success"
+	reason := templates.ReasonForError(result.Err)
+	if result.Err != nil {
+		outcome = "failure"
+	}
+	for _, ready := range result.ReadyDurations {
+		readyDurationHistogram.WithLabelValues(result.Template, ready.Kind).Observe(ready.Duration)
+	}
+	for _, phase := range result.PhaseDurations {
+		phaseDurationHistogram.WithLabelValues(result.Template, phase.Phase).Observe(phase.Duration)
+	}
+	testGauge.WithLabelValues(result.Template, outcome, reason).SetToCurrentTime()
+	launchHistogram.WithLabelValues(result.Template, outcome, reason).Observe(result.LaunchDuration)
+	durationHistogram.WithLabelValues(result.Template, outcome, reason).Observe(result.TotalDuration)
+	if !keepObjects {
+		teardownGauge.WithLabelValues(result.Template, outcome, reason).Set(result.TeardownDuration)
+	}
+	for _, hookResult := range result.HookResults {
+		hookOutcome := "success"
+		if hookResult.Err != nil {
+			hookOutcome = "failure"
+		}
+		hookGauge.WithLabelValues(hookResult.Name, string(hookResult.Phase), hookOutcome).SetToCurrentTime()
 	}
 }
 
-func doSmoketest(testGauge, launchGauge, durationGauge *prometheus.GaugeVec) {
-	var launchDuration, totalDuration float64
-	start := time.Now()
-	test, err := templates.NewSmoketest()
+func doProjectSmoketest(testGauge, provisionGauge, teardownGauge *prometheus.GaugeVec) {
+	test, err := projects.NewSmoketest()
 	if err != nil {
-		totalDuration = time.Now().Sub(start).Seconds()
-		glog.Errorf("Failed initiating smoketest: %s", err)
-		publishResult(testGauge, launchGauge, durationGauge, launchDuration, totalDuration, err)
+		glog.Errorf("Failed initiating project smoketest: %s", err)
+		publishProjectResult(testGauge, provisionGauge, teardownGauge, 0, 0, err)
 		return
 	}
-	launchDuration, err = test.Run(keepObjects, timeout)
-	totalDuration = time.Now().Sub(start).Seconds()
-	publishResult(testGauge, launchGauge, durationGauge, launchDuration, totalDuration, err)
+	provisionDuration, teardownDuration, err := test.Run(keepObjects, int(timeout.Seconds()))
+	publishProjectResult(testGauge, provisionGauge, teardownGauge, provisionDuration, teardownDuration, err)
 }
 
-func publishResult(testGauge, launchGauge, durationGauge *prometheus.GaugeVec, launchDuration, totalDuration float64, err error) {
+func publishProjectResult(testGauge, provisionGauge, teardownGauge *prometheus.GaugeVec, provisionDuration, teardownDuration float64, err error) {
 	result := "success"
 	var reason string
 	if err != nil {
@@ -128,6 +286,6 @@ func publishResult(testGauge, launchGauge, durationGauge *prometheus.GaugeVec, l
 		reason = err.Error()
 	}
 	testGauge.WithLabelValues(result, reason).SetToCurrentTime()
-	launchGauge.WithLabelValues(result, reason).Set(launchDuration)
-	durationGauge.WithLabelValues(result, reason).Set(totalDuration)
+	provisionGauge.WithLabelValues(result, reason).Set(provisionDuration)
+	teardownGauge.WithLabelValues(result, reason).Set(teardownDuration)
 }
@@ -0,0 +1,240 @@
+package projects
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	projectv1 "github.com/openshift/api/project/v1"
+	projectv1client "github.com/openshift/client-go/project/clientset/versioned/typed/project/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	// ErrInitTest is returned if the Smoketest could not be initialized.
+	ErrInitTest = errors.New("InitTestFailed")
+	// ErrCreateProject is returned if the Smoketest could not create the `ProjectRequest`.
+	ErrCreateProject = errors.New("CreateProjectFailed")
+	// ErrProvisionTimeout is returned if the Smoketest timed out waiting for the `Project` to become active.
+	ErrProvisionTimeout = errors.New("ProvisionProjectTimeout")
+	// ErrRBACProbeFailed is returned if the probe objects could not be created in the new `Project`,
+	// indicating that the project's RBAC bootstrap did not complete correctly.
+	ErrRBACProbeFailed = errors.New("RBACProbeFailed")
+	// ErrTeardownTimeout is returned if the Smoketest timed out waiting for the `Project` to be fully removed.
+	ErrTeardownTimeout = errors.New("TeardownProjectTimeout")
+	// ErrUnknown is returned if an error unrelated to the test is found.
+	ErrUnknown = errors.New("Unknown")
+)
+
+// Smoketest runs sanity checks against the OpenShift Project lifecycle controllers.
+type Smoketest struct {
+	projectInterface projectv1client.ProjectV1Interface
+	k8sInterface     kubernetes.Interface
+}
+
+// NewSmoketest creates a new `Smoketest` instance to run sanity checks and configures the OpenShift API client.
+func NewSmoketest() (*Smoketest, error) {
+	smoketest := &Smoketest{}
+	err := smoketest.init()
+	return smoketest, err
+}
+
+func (t *Smoketest) init() error {
+	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	)
+	// Get a rest.Config from the kubeconfig file.  This will be passed into all
+	// the client objects we create.
+	restconfig, err := kubeconfig.ClientConfig()
+	if err != nil {
+		glog.Warningf("Failed to create kubernetes REST client config: %v", err)
+		return ErrInitTest
+	}
+	pClient, err := projectv1client.NewForConfig(restconfig)
+	if err != nil {
+		glog.Warningf("Failed to create kubernetes REST client for projects: %v", err)
+		return ErrInitTest
+	}
+	k8sClient, err := kubernetes.NewForConfig(restconfig)
+	if err != nil {
+		glog.Warningf("Failed to create kubernetes REST client for core: %v", err)
+		return ErrInitTest
+	}
+	t.projectInterface = pClient
+	t.k8sInterface = k8sClient
+	return nil
+}
+
+// Run executes the smoketest for the `Project` lifecycle controller.
+// This will perform the following actions:
+//
+// 1. Create a `ProjectRequest`, and wait for the resulting `Project` to become `Active`.
+// 2. Create probe objects (a `ConfigMap` and a `RoleBinding`) in the new project to verify that RBAC was bootstrapped.
+// 3. Delete the `Project`, and wait for it to be fully removed from the cluster.
+//
+// It returns the provisioning duration, the teardown duration, and an error if any check failed.
+func (t *Smoketest) Run(keepObjects bool, timeout int) (float64, float64, error) {
+	id := strconv.FormatInt(time.Now().Unix(), 10)
+	glog.V(1).Infof("Started running project smoketest %s", id)
+	defer glog.V(1).Infof("Completed project smoketest %s", id)
+	project, provisionDuration, err := t.createProjectCheck(id, timeout)
+	if err != nil {
+		glog.Warningf("Failed testing project provisioning: %v", err)
+		return provisionDuration, 0, err
+	}
+	if err := t.rbacProbeCheck(project.Name); err != nil {
+		glog.Warningf("Failed testing project RBAC bootstrap: %v", err)
+		if !keepObjects {
+			t.deleteProjectCheck(project.Name, timeout)
+		}
+		return provisionDuration, 0, err
+	}
+	if keepObjects {
+		glog.V(1).Infof("Successfully ran project smoketest %s", id)
+		return provisionDuration, 0, nil
+	}
+	teardownDuration, err := t.deleteProjectCheck(project.Name, timeout)
+	if err != nil {
+		glog.Warningf("Failed testing project teardown: %v", err)
+		return provisionDuration, teardownDuration, err
+	}
+	glog.V(1).Infof("Successfully ran project smoketest %s", id)
+	return provisionDuration, teardownDuration, nil
+}
+
+// createProjectCheck runs a smoke test to ensure that a `ProjectRequest` results in an `Active` `Project`.
+func (t *Smoketest) createProjectCheck(id string, timeoutInterval int) (*projectv1.Project, float64, error) {
+	glog.V(1).Info("Checking that a project can be created")
+	defer glog.V(1).Info("Completed project creation check")
+	var duration float64
+	projectName := fmt.Sprintf("smoketest-project-%s", id)
+	request := &projectv1.ProjectRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: projectName,
+		},
+		DisplayName: projectName,
+		Description: "Created by the project lifecycle smoketest",
+	}
+	start := time.Now()
+	_, err := t.projectInterface.ProjectRequests().Create(request)
+	if err != nil {
+		glog.Warningf("Failed to create project request %s: %v", projectName, err)
+		return nil, duration, ErrCreateProject
+	}
+	glog.V(2).Infof("Created project request %s", projectName)
+	watcher, err := t.projectInterface.Projects().Watch(metav1.SingleObject(metav1.ObjectMeta{Name: projectName}))
+	if err != nil {
+		glog.Warningf("Failed to watch project %s: %v", projectName, err)
+		return nil, duration, ErrUnknown
+	}
+	defer watcher.Stop()
+	glog.V(2).Infof("Waiting for project %s to become active...", projectName)
+	timeout := time.After(time.Duration(timeoutInterval) * time.Second)
+	for {
+		select {
+		case <-timeout:
+			duration = time.Now().Sub(start).Seconds()
+			return nil, duration, ErrProvisionTimeout
+		case event := <-watcher.ResultChan():
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				project := event.Object.(*projectv1.Project)
+				if project.Status.Phase == corev1.NamespaceActive {
+					duration = time.Now().Sub(start).Seconds()
+					glog.V(2).Infof("Project %s is active", project.Name)
+					return project, duration, nil
+				}
+			default:
+				duration = time.Now().Sub(start).Seconds()
+				glog.Errorf("Unexpected event type %s watching project %s", event.Type, projectName)
+				return nil, duration, ErrUnknown
+			}
+		}
+	}
+}
+
+// rbacProbeCheck creates probe objects in the given project to verify that RBAC was bootstrapped correctly.
+func (t *Smoketest) rbacProbeCheck(namespace string) error {
+	glog.V(1).Info("Checking that RBAC is bootstrapped in the new project")
+	defer glog.V(1).Info("Completed RBAC bootstrap check")
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "smoketest-rbac-probe",
+		},
+		Data: map[string]string{
+			"probe": "true",
+		},
+	}
+	_, err := t.k8sInterface.CoreV1().ConfigMaps(namespace).Create(configMap)
+	if err != nil {
+		glog.Warningf("Failed to create probe config map in project %s: %v", namespace, err)
+		return ErrRBACProbeFailed
+	}
+	glog.V(2).Infof("Created probe config map in project %s", namespace)
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "smoketest-rbac-probe",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "default",
+				Namespace: namespace,
+			},
+		},
+	}
+	_, err = t.k8sInterface.RbacV1().RoleBindings(namespace).Create(roleBinding)
+	if err != nil {
+		glog.Warningf("Failed to create probe role binding in project %s: %v", namespace, err)
+		return ErrRBACProbeFailed
+	}
+	glog.V(2).Infof("Validated RBAC bootstrap in project %s", namespace)
+	return nil
+}
+
+// deleteProjectCheck deletes the named project and waits for it to be fully removed from the cluster.
+func (t *Smoketest) deleteProjectCheck(name string, timeoutInterval int) (float64, error) {
+	glog.V(1).Info("Checking that a project can be deleted")
+	defer glog.V(1).Info("Completed project deletion check")
+	var duration float64
+	start := time.Now()
+	watcher, err := t.projectInterface.Projects().Watch(metav1.SingleObject(metav1.ObjectMeta{Name: name}))
+	if err != nil {
+		glog.Warningf("Failed to watch project %s for deletion: %v", name, err)
+		return duration, ErrUnknown
+	}
+	defer watcher.Stop()
+	err = t.projectInterface.Projects().Delete(name, &metav1.DeleteOptions{})
+	if err != nil {
+		glog.Warningf("Failed to delete project %s: %v", name, err)
+		return duration, ErrUnknown
+	}
+	glog.V(2).Infof("Deleted project %s", name)
+	timeout := time.After(time.Duration(timeoutInterval) * time.Second)
+	for {
+		select {
+		case <-timeout:
+			duration = time.Now().Sub(start).Seconds()
+			return duration, ErrTeardownTimeout
+		case event := <-watcher.ResultChan():
+			if event.Type == watch.Deleted {
+				duration = time.Now().Sub(start).Seconds()
+				glog.V(2).Infof("Project %s fully removed", name)
+				return duration, nil
+			}
+		}
+	}
+}
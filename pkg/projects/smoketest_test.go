@@ -0,0 +1,23 @@
+package projects
+
+import (
+	"testing"
+
+	fakeCore "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRBACProbeCheckSmoketest(t *testing.T) {
+	fakeClient := fakeCore.NewSimpleClientset()
+	smoketest := &Smoketest{
+		k8sInterface: fakeClient,
+	}
+	err := smoketest.rbacProbeCheck("dummyNamespace")
+	if err != nil {
+		t.Errorf("RBAC probe check failed: %s", err)
+	}
+}
+
+// TODO: Add unit tests for the following:
+// NewSmoketest
+// createProjectCheck
+// deleteProjectCheck
@@ -0,0 +1,149 @@
+package templates
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/glog"
+	templatev1 "github.com/openshift/api/template/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// deletePollInterval is how often child object presence is re-checked while waiting for teardown.
+const deletePollInterval = 2 * time.Second
+
+// errUnsupportedKind is returned internally by getChildUID for a Kind this package has no typed
+// client for. It is not a test failure: teardown of such a child cannot be verified, so it is
+// treated as already gone.
+var errUnsupportedKind = errors.New("unsupported kind")
+
+// waitForDeletion deletes a TemplateInstance and waits for it, and every object listed in its
+// Status.Objects, to be fully removed from the cluster. It captures each object's UID before
+// issuing the delete, modeled on kubectl's UID-map delete verification: an object later observed
+// under the same name but a different UID has already been removed and recreated by something
+// else, so it does not count as a leftover of this run.
+func (t *Smoketest) waitForDeletion(namespace string, instance *templatev1.TemplateInstance, timeoutInterval int) (float64, error) {
+	if instance == nil {
+		return 0, nil
+	}
+	start := time.Now()
+	instanceUID := instance.UID
+	children := make([]corev1.ObjectReference, len(instance.Status.Objects))
+	for i, obj := range instance.Status.Objects {
+		children[i] = obj.Ref
+	}
+	duration := func() float64 {
+		return time.Now().Sub(start).Seconds()
+	}
+
+	if err := t.deleteTemplateInstance(namespace, instance); err != nil {
+		return duration(), err
+	}
+
+	deadline := time.Now().Add(time.Duration(timeoutInterval) * time.Second)
+	if err := t.waitForInstanceGone(namespace, instance.Name, instanceUID, deadline); err != nil {
+		glog.Warningf("Template instance %s was not fully removed before the teardown timeout: %v", instance.Name, err)
+		return duration(), err
+	}
+	for _, ref := range children {
+		if err := t.waitForChildGone(namespace, ref, deadline); err != nil {
+			glog.Warningf("Object %s %s was not removed before the teardown timeout: %v", ref.Kind, ref.Name, err)
+			return duration(), err
+		}
+	}
+	glog.V(2).Infof("Template instance %s and all child objects were fully removed", instance.Name)
+	return duration(), nil
+}
+
+// waitForInstanceGone polls until the named TemplateInstance 404s, or is observed with a UID
+// different from the one captured before deletion.
+func (t *Smoketest) waitForInstanceGone(namespace string, name string, uid types.UID, deadline time.Time) error {
+	err := wait.PollImmediate(deletePollInterval, time.Until(deadline), func() (bool, error) {
+		ti, err := t.templateInterface.TemplateInstances(namespace).Get(name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return ti.UID != uid, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return ErrTeardownIncomplete
+	}
+	return err
+}
+
+// waitForChildGone polls until the referenced object 404s, or is observed with a UID different
+// from the one captured before deletion.
+func (t *Smoketest) waitForChildGone(namespace string, ref corev1.ObjectReference, deadline time.Time) error {
+	err := wait.PollImmediate(deletePollInterval, time.Until(deadline), func() (bool, error) {
+		uid, err := t.getChildUID(namespace, ref.Kind, ref.Name)
+		if err == errUnsupportedKind {
+			glog.V(2).Infof("Skipping teardown verification for unsupported kind %s", ref.Kind)
+			return true, nil
+		}
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return uid != ref.UID, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return ErrTeardownIncomplete
+	}
+	return err
+}
+
+// getChildUID fetches the current UID of a single object created by a TemplateInstance.
+func (t *Smoketest) getChildUID(namespace string, kind string, name string) (types.UID, error) {
+	obj, err := t.getChildObject(namespace, kind, name)
+	if err != nil {
+		return "", err
+	}
+	return obj.GetUID(), nil
+}
+
+// getChildLabels fetches the current labels of a single object created by a TemplateInstance.
+func (t *Smoketest) getChildLabels(namespace string, kind string, name string) (map[string]string, error) {
+	obj, err := t.getChildObject(namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.GetLabels(), nil
+}
+
+// getChildObject fetches a single object created by a TemplateInstance, identified by Kind and
+// Name, returning errUnsupportedKind for a Kind this package has no typed client for.
+func (t *Smoketest) getChildObject(namespace string, kind string, name string) (metav1.Object, error) {
+	switch kind {
+	case "ConfigMap":
+		return t.k8sInterface.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	case "Secret":
+		return t.k8sInterface.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	case "Service":
+		return t.k8sInterface.CoreV1().Services(namespace).Get(name, metav1.GetOptions{})
+	case "Job":
+		return t.k8sInterface.BatchV1().Jobs(namespace).Get(name, metav1.GetOptions{})
+	case "Pod":
+		return t.k8sInterface.CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+	case "PersistentVolumeClaim":
+		return t.k8sInterface.CoreV1().PersistentVolumeClaims(namespace).Get(name, metav1.GetOptions{})
+	case "Deployment":
+		return t.k8sInterface.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	case "DaemonSet":
+		return t.k8sInterface.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+	case "StatefulSet":
+		return t.k8sInterface.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+	case "DeploymentConfig":
+		return t.appsInterface.DeploymentConfigs(namespace).Get(name, metav1.GetOptions{})
+	default:
+		return nil, errUnsupportedKind
+	}
+}
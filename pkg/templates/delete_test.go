@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakeCore "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWaitForChildGone(t *testing.T) {
+	cases := []struct {
+		name    string
+		objects []runtime.Object
+		ref     corev1.ObjectReference
+		wantErr bool
+	}{
+		{
+			name: "object already deleted",
+			ref:  corev1.ObjectReference{Kind: "ConfigMap", Name: "missing", UID: types.UID("original")},
+		},
+		{
+			name: "object recreated with a different uid",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "recreated", Namespace: "dummyNamespace", UID: types.UID("new")}},
+			},
+			ref: corev1.ObjectReference{Kind: "ConfigMap", Name: "recreated", UID: types.UID("original")},
+		},
+		{
+			name: "unsupported kind is treated as already gone",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "irrelevant", UID: types.UID("original")}},
+			},
+			ref: corev1.ObjectReference{Kind: "Route", Name: "irrelevant", UID: types.UID("original")},
+		},
+		{
+			name: "object still present with the original uid",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "leftover", Namespace: "dummyNamespace", UID: types.UID("original")}},
+			},
+			ref:     corev1.ObjectReference{Kind: "ConfigMap", Name: "leftover", UID: types.UID("original")},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClient := fakeCore.NewSimpleClientset(c.objects...)
+			smoketest := &Smoketest{k8sInterface: fakeClient}
+			deadline := time.Now().Add(10 * time.Millisecond)
+			err := smoketest.waitForChildGone("dummyNamespace", c.ref, deadline)
+			if c.wantErr && err != ErrTeardownIncomplete {
+				t.Errorf("waitForChildGone() = %v, want ErrTeardownIncomplete", err)
+			}
+			if !c.wantErr && err != nil {
+				t.Errorf("waitForChildGone() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// TODO: Add unit tests for the following:
+// waitForDeletion
+// waitForInstanceGone
+// getChildUID
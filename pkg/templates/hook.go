@@ -0,0 +1,102 @@
+package templates
+
+import (
+	"sort"
+
+	"github.com/golang/glog"
+	templatev1 "github.com/openshift/api/template/v1"
+)
+
+// Phase identifies a point in the smoketest pipeline at which Hooks may run.
+type Phase string
+
+const (
+	// PhasePreCreate runs before a spec's Template is created.
+	PhasePreCreate Phase = "PreCreate"
+	// PhasePreInstantiate runs after the Template is created, before the TemplateInstance is launched.
+	PhasePreInstantiate Phase = "PreInstantiate"
+	// PhasePostReady runs once the TemplateInstance (and, if RunOptions.Wait is set, its children) report ready.
+	PhasePostReady Phase = "PostReady"
+	// PhasePostDelete runs after the TemplateInstance and its children have been torn down.
+	PhasePostDelete Phase = "PostDelete"
+)
+
+// Hook is a pluggable action the smoketest pipeline runs at a given Phase, ordered by Weight.
+// Built-in hooks register themselves via RegisterHook from an init() function; user-defined hooks
+// may do the same, or be discovered at startup with LoadHookPlugins.
+type Hook interface {
+	// Name uniquely identifies the hook. It labels the hook's metrics, and breaks ties in Weight order.
+	Name() string
+	// Weight orders hooks within a Phase; lower weights run first.
+	Weight() int
+	// Phase is the pipeline transition at which the hook runs.
+	Phase() Phase
+	// Run executes the hook against the current smoketest run.
+	Run(ctx HookContext) error
+}
+
+// HookContext carries the state of the current smoketest run that a Hook may act on. Fields are
+// populated incrementally as the run progresses; a Hook should only rely on fields relevant to its
+// own Phase (e.g. Template is unset during PhasePreCreate).
+type HookContext struct {
+	Smoketest *Smoketest
+	Namespace string
+	Spec      *TemplateSpec
+	ID        string
+	Template  *templatev1.Template
+	Instance  *templatev1.TemplateInstance
+	Params    map[string]string
+	Result    *Result
+}
+
+// HookResult captures the outcome of running a single Hook, for metrics reporting.
+type HookResult struct {
+	// Name is the Hook's name.
+	Name string
+	// Phase is the Phase the Hook ran in.
+	Phase Phase
+	// Err is non-nil if the Hook failed.
+	Err error
+}
+
+// registeredHooks holds every Hook registered via RegisterHook, in registration order.
+var registeredHooks []Hook
+
+// RegisterHook adds a Hook to the set run by every Smoketest. Intended to be called from an
+// init() function of a package imported for its side effects, or by LoadHookPlugins.
+func RegisterHook(hook Hook) {
+	registeredHooks = append(registeredHooks, hook)
+}
+
+// hooksForPhase returns the hooks registered for phase, ordered by ascending Weight, ties broken by Name.
+func hooksForPhase(phase Phase) []Hook {
+	matched := make([]Hook, 0, len(registeredHooks))
+	for _, hook := range registeredHooks {
+		if hook.Phase() == phase {
+			matched = append(matched, hook)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Weight() != matched[j].Weight() {
+			return matched[i].Weight() < matched[j].Weight()
+		}
+		return matched[i].Name() < matched[j].Name()
+	})
+	return matched
+}
+
+// runHooks runs every hook registered for phase, in weight order, recording a HookResult on
+// ctx.Result for each. A failing hook does not stop the hooks that follow it.
+func runHooks(phase Phase, ctx HookContext) {
+	for _, hook := range hooksForPhase(phase) {
+		err := hook.Run(ctx)
+		if err != nil {
+			glog.Warningf("Hook %s failed in phase %s: %v", hook.Name(), phase, err)
+		} else {
+			glog.V(2).Infof("Hook %s ran in phase %s", hook.Name(), phase)
+		}
+		if ctx.Result != nil {
+			ctx.Result.HookResults = append(ctx.Result.HookResults, HookResult{Name: hook.Name(), Phase: phase, Err: err})
+		}
+	}
+}
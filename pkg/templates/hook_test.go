@@ -0,0 +1,59 @@
+package templates
+
+import (
+	"testing"
+)
+
+// fakeHook is a minimal Hook implementation for exercising hooksForPhase without any real
+// side effects.
+type fakeHook struct {
+	name   string
+	weight int
+	phase  Phase
+}
+
+func (h *fakeHook) Name() string          { return h.name }
+func (h *fakeHook) Weight() int           { return h.weight }
+func (h *fakeHook) Phase() Phase          { return h.phase }
+func (h *fakeHook) Run(HookContext) error { return nil }
+
+func TestHooksForPhase(t *testing.T) {
+	saved := registeredHooks
+	defer func() { registeredHooks = saved }()
+
+	registeredHooks = []Hook{
+		&fakeHook{name: "b", weight: 1, phase: PhasePreCreate},
+		&fakeHook{name: "a", weight: 1, phase: PhasePreCreate},
+		&fakeHook{name: "first", weight: 0, phase: PhasePreCreate},
+		&fakeHook{name: "other-phase", weight: -1, phase: PhasePostReady},
+	}
+
+	got := hooksForPhase(PhasePreCreate)
+	want := []string{"first", "a", "b"}
+	if len(got) != len(want) {
+		t.Fatalf("hooksForPhase() returned %d hooks, want %d", len(got), len(want))
+	}
+	for i, name := range want {
+		if got[i].Name() != name {
+			t.Errorf("hooksForPhase()[%d] = %s, want %s", i, got[i].Name(), name)
+		}
+	}
+}
+
+func TestHooksForPhaseNoMatches(t *testing.T) {
+	saved := registeredHooks
+	defer func() { registeredHooks = saved }()
+
+	registeredHooks = []Hook{
+		&fakeHook{name: "only", weight: 0, phase: PhasePreCreate},
+	}
+
+	got := hooksForPhase(PhasePostDelete)
+	if len(got) != 0 {
+		t.Errorf("hooksForPhase() = %v, want empty", got)
+	}
+}
+
+// TODO: Add unit tests for the following:
+// RegisterHook
+// runHooks
@@ -0,0 +1,131 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func init() {
+	RegisterHook(&imagePullWarmupHook{})
+	RegisterHook(&webhookNotificationHook{})
+}
+
+// warmupImage is the image imagePullWarmupHook pre-pulls, so a Job in a spec's Template that runs
+// this image does not pay a cold-pull cost on its own.
+const warmupImage = "centos:7"
+
+// warmupPollInterval is how often imagePullWarmupHook re-checks its throwaway Pod's phase.
+const warmupPollInterval = 2 * time.Second
+
+// warmupTimeout bounds how long imagePullWarmupHook waits for the image pull to complete.
+const warmupTimeout = 60 * time.Second
+
+// webhookTimeout bounds how long webhookNotificationHook waits for its POST to complete. Hooks run
+// synchronously inline in the pipeline, so an unresponsive endpoint must not be allowed to hang a
+// smoketest run indefinitely.
+const webhookTimeout = 10 * time.Second
+
+// webhookClient is used instead of http.DefaultClient, which has no timeout.
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// imagePullWarmupHook pre-pulls warmupImage via a throwaway Pod before a TemplateInstance's Job
+// runs, so a slow image pull is never mistaken for a regression in the Template or Job controllers.
+type imagePullWarmupHook struct{}
+
+func (h *imagePullWarmupHook) Name() string { return "image-pull-warmup" }
+func (h *imagePullWarmupHook) Weight() int  { return 0 }
+func (h *imagePullWarmupHook) Phase() Phase { return PhasePreInstantiate }
+
+func (h *imagePullWarmupHook) Run(ctx HookContext) error {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("smoketest-warmup-%s", ctx.ID),
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    "warmup",
+					Image:   warmupImage,
+					Command: []string{"/bin/true"},
+				},
+			},
+		},
+	}
+	created, err := ctx.Smoketest.k8sInterface.CoreV1().Pods(ctx.Namespace).Create(pod)
+	if err != nil {
+		return fmt.Errorf("failed to create image pull warmup pod: %v", err)
+	}
+	defer ctx.Smoketest.k8sInterface.CoreV1().Pods(ctx.Namespace).Delete(created.Name, &metav1.DeleteOptions{})
+
+	return wait.PollImmediate(warmupPollInterval, warmupTimeout, func() (bool, error) {
+		current, err := ctx.Smoketest.k8sInterface.CoreV1().Pods(ctx.Namespace).Get(created.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch current.Status.Phase {
+		case corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed:
+			return true, nil
+		default:
+			return false, nil
+		}
+	})
+}
+
+// webhookURL is the destination webhookNotificationHook POSTs its run summary to. It is unset
+// (disabling the hook) unless the caller configures it with SetWebhookURL.
+var webhookURL string
+
+// SetWebhookURL configures the URL webhookNotificationHook POSTs a JSON run summary to after a spec
+// run's TemplateInstance teardown. No summary is posted for a run whose objects are kept, or whose
+// Template could not be created. Passing an empty string disables the hook. Intended to be called once at startup from
+// a configured flag, matching cmd/monitor's other `-flag` to package-option wiring.
+func SetWebhookURL(url string) {
+	webhookURL = url
+}
+
+// webhookNotificationHook POSTs a JSON summary of a spec's Result to a configurable URL once the
+// TemplateInstance and its children have been torn down.
+type webhookNotificationHook struct{}
+
+func (h *webhookNotificationHook) Name() string { return "webhook-notification" }
+func (h *webhookNotificationHook) Weight() int  { return 100 }
+func (h *webhookNotificationHook) Phase() Phase { return PhasePostDelete }
+
+func (h *webhookNotificationHook) Run(ctx HookContext) error {
+	if webhookURL == "" {
+		return nil
+	}
+	summary := struct {
+		Template string `json:"template"`
+		Reason   string `json:"reason"`
+		Result   Result `json:"result"`
+	}{
+		Template: ctx.Spec.Name,
+		// Result.Err is a bare error interface, which encoding/json marshals to "{}" for this
+		// package's sentinel errors. Surface the failure reason as its own string field so a
+		// failure notification actually says what went wrong.
+		Reason: ReasonForError(ctx.Result.Err),
+		Result: *ctx.Result,
+	}
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run summary: %v", err)
+	}
+	resp, err := webhookClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post run summary to %s: %v", webhookURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook at %s returned status %d", webhookURL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package templates
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"plugin"
+
+	"github.com/golang/glog"
+)
+
+// hookPluginSymbol is the exported symbol a hook plugin must define. It must be a value
+// implementing the Hook interface, e.g.:
+//
+//	var Hook myHook
+type hookPluginSymbol = Hook
+
+// LoadHookPlugins loads every Go plugin (`*.so`) in dir and registers the Hook each one exports
+// under the symbol name "Hook". It complements the built-in hooks registered from this package's
+// init(), letting operators add hooks without forking the smoketest binary.
+func LoadHookPlugins(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read hook plugin directory %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := loadHookPlugin(path); err != nil {
+			return fmt.Errorf("failed to load hook plugin %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func loadHookPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+	sym, err := p.Lookup("Hook")
+	if err != nil {
+		return err
+	}
+	hook, ok := sym.(hookPluginSymbol)
+	if !ok {
+		return fmt.Errorf("exported Hook symbol does not implement templates.Hook")
+	}
+	RegisterHook(hook)
+	glog.V(0).Infof("Registered hook %s from plugin %s", hook.Name(), path)
+	return nil
+}
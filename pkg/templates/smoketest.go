@@ -1,15 +1,15 @@
 package templates
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"reflect"
+	"io/ioutil"
 	"strconv"
 	"time"
 
 	"github.com/golang/glog"
 	templatev1 "github.com/openshift/api/template/v1"
+	appsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
 	projectv1client "github.com/openshift/client-go/project/clientset/versioned/typed/project/v1"
 	templatev1client "github.com/openshift/client-go/template/clientset/versioned/typed/template/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -18,52 +18,19 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
 )
 
-const configMapJSON = `
-{
-	"apiVersion": "v1",
-	"kind": "ConfigMap",
-	"metadata": {
-		"name": "test-configmap-${ID}"
-	},
-	"data": {
-		"foo": "bar",
-		"simpleParam": "${SIMPLE_PARAM}"
-	}
-}
-`
-
-// centos:7 should be cached in an OpenShift cluster
-const jobJSON = `
-{
-	"apiVersion":  "batch/v1",
-	"kind": "Job",
-	"metadata": {
-		"name": "test-job-${ID}"
-	},
-	"spec": {
-		"backoffLimit": 1,
-		"template": {
-			"spec": {
-				"restartPolicy": "Never",
-				"containers": [
-					{
-						"name": "bash",
-						"image": "centos:7",
-						"command": [ "/bin/bash", "-c", "--" ],
-						"args": "${{JSON_PARAM}}"
-					}
-				]
-			}
-		}
-	}
-}
-`
+// defaultSpecsConfigMap is the name of the ConfigMap to load TemplateSpecs from when no
+// `-templates-dir` is configured.
+const defaultSpecsConfigMap = "smoketest-templates"
 
 var (
 	// ErrInitTest is returned if the Smoketest could not be initialized.
 	ErrInitTest = errors.New("InitTestFailed")
+	// ErrNoTemplateSpecs is returned if no TemplateSpecs could be loaded for the Smoketest to run.
+	ErrNoTemplateSpecs = errors.New("NoTemplateSpecsFound")
 	// ErrCreateTemplate is returned if the Smoketest could not create the `Template`.
 	ErrCreateTemplate = errors.New("CreateTemplateFailed")
 	// ErrCreateInstance is returend if the Smoketest could not create the `TemplateInstance`.
@@ -75,26 +42,117 @@ var (
 	ErrLaunchInstanceTimeout = errors.New("LaunchTemplateInstanceTimeout")
 	// ErrInstanceInvalid is returned if the `TemplateInstance` was not configured properly.
 	ErrInstanceInvalid = errors.New("ValidateTemplateInstanceFailed")
+	// ErrObjectNotReadyTimeout is returned if a TemplateInstance's child object did not become
+	// ready before the configured wait timeout elapsed.
+	ErrObjectNotReadyTimeout = errors.New("ObjectNotReadyTimeout")
+	// ErrTeardownIncomplete is returned if a TemplateInstance, or one of its child objects, still
+	// existed after the configured teardown timeout elapsed.
+	ErrTeardownIncomplete = errors.New("TeardownIncomplete")
 	// ErrUnknown is returned if an error unrelated to the test is found.
 	ErrUnknown = errors.New("Unknown")
 )
 
+// ReasonForError maps an error returned by this package to the name of the sentinel Err* variable
+// it corresponds to, for use as a fixed-cardinality Prometheus label value instead of err.Error().
+// An error that matches none of the sentinels below, such as an API error passed through from
+// readiness or teardown polling, maps to ErrUnknown's name rather than widening the label's cardinality.
+func ReasonForError(err error) string {
+	switch err {
+	case nil:
+		return ""
+	case ErrInitTest:
+		return "ErrInitTest"
+	case ErrNoTemplateSpecs:
+		return "ErrNoTemplateSpecs"
+	case ErrCreateTemplate:
+		return "ErrCreateTemplate"
+	case ErrCreateInstance:
+		return "ErrCreateInstance"
+	case ErrLaunchInstanceFailed:
+		return "ErrLaunchInstanceFailed"
+	case ErrLaunchInstanceTimeout:
+		return "ErrLaunchInstanceTimeout"
+	case ErrInstanceInvalid:
+		return "ErrInstanceInvalid"
+	case ErrObjectNotReadyTimeout:
+		return "ErrObjectNotReadyTimeout"
+	case ErrTeardownIncomplete:
+		return "ErrTeardownIncomplete"
+	default:
+		return "ErrUnknown"
+	}
+}
+
+// RunOptions configures how Smoketest.Run exercises each TemplateSpec.
+type RunOptions struct {
+	// KeepObjects, if true, skips cleanup of objects the smoketest creates.
+	KeepObjects bool
+	// Timeout bounds how long to wait for a TemplateInstance to report ready.
+	Timeout time.Duration
+	// Wait, if true, polls each of a ready TemplateInstance's child objects until they are
+	// individually ready (e.g. Pods running, Deployments rolled out), not just created.
+	Wait bool
+	// WaitTimeout bounds how long to wait for child objects to become ready, in seconds.
+	WaitTimeout int
+	// TeardownTimeout bounds how long to wait for a deleted TemplateInstance and its child
+	// objects to be fully removed from the cluster, in seconds.
+	TeardownTimeout int
+}
+
+// Result captures the outcome of running a single TemplateSpec through the smoketest pipeline.
+type Result struct {
+	// Template is the name of the TemplateSpec this result belongs to.
+	Template string
+	// LaunchDuration is how long the TemplateInstance took to launch, or 0 if it never launched.
+	LaunchDuration float64
+	// TotalDuration is how long the whole spec took to run, start to finish.
+	TotalDuration float64
+	// ReadyDurations records how long each child object took to become individually ready, if
+	// RunOptions.Wait was set.
+	ReadyDurations []KindDuration
+	// TeardownDuration is how long it took to verify that the TemplateInstance and its child
+	// objects were fully removed, or 0 if objects were kept.
+	TeardownDuration float64
+	// PhaseDurations records how long each named pipeline phase took, in the order it ran, so
+	// operators can compute per-phase latency percentiles.
+	PhaseDurations []PhaseDuration
+	// HookResults records the outcome of every Hook run during this spec's run, in the order they ran.
+	HookResults []HookResult
+	// Err is non-nil if any stage of the spec's run failed.
+	Err error
+}
+
+// PhaseDuration records how long a single named phase of the smoketest pipeline took to run.
+type PhaseDuration struct {
+	// Phase is the pipeline phase's name, e.g. "create_template" or "wait_ready".
+	Phase string
+	// Duration is how long the phase took, in seconds.
+	Duration float64
+}
+
 // Smoketest runs sanity checks against the OpenShift Template and TemplateInstance controllers.
 type Smoketest struct {
 	namespace         string
 	templateInterface templatev1client.TemplateV1Interface
 	projectInterface  projectv1client.ProjectV1Interface
+	appsInterface     appsv1client.AppsV1Interface
 	k8sInterface      kubernetes.Interface
+	specs             []*TemplateSpec
 }
 
-// NewSmoketest creates a new `Smoketest` instance to run sanity checks and configures the OpenShift API client.
-func NewSmoketest() (*Smoketest, error) {
+// NewSmoketest creates a new `Smoketest` instance to run sanity checks and configures the OpenShift
+// API client. TemplateSpecs are loaded from templatesDir, or from the `smoketest-templates` ConfigMap
+// in the current namespace if templatesDir is empty. A spec's relative manifestPath is resolved
+// against templatesDir when specs are loaded from disk, or against manifestsDir when they are
+// loaded from the ConfigMap (manifests still have to be available on disk, e.g. mounted from a
+// ConfigMap volume, at manifestsDir).
+func NewSmoketest(templatesDir string, manifestsDir string) (*Smoketest, error) {
 	smoketest := &Smoketest{}
-	err := smoketest.init()
+	err := smoketest.init(templatesDir, manifestsDir)
 	return smoketest, err
 }
 
-func (t *Smoketest) init() error {
+func (t *Smoketest) init(templatesDir string, manifestsDir string) error {
 	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		clientcmd.NewDefaultClientConfigLoadingRules(),
 		&clientcmd.ConfigOverrides{},
@@ -122,6 +180,11 @@ func (t *Smoketest) init() error {
 		glog.Warningf("Failed to create kubernetes REST client for projects: %v", err)
 		return ErrInitTest
 	}
+	aClient, err := appsv1client.NewForConfig(restconfig)
+	if err != nil {
+		glog.Warningf("Failed to create kubernetes REST client for apps: %v", err)
+		return ErrInitTest
+	}
 	k8sClient, err := kubernetes.NewForConfig(restconfig)
 	if err != nil {
 		glog.Warningf("failed to create kubernetes REST client for core: %v", err)
@@ -129,84 +192,136 @@ func (t *Smoketest) init() error {
 	}
 	t.templateInterface = tClient
 	t.projectInterface = pClient
+	t.appsInterface = aClient
 	t.k8sInterface = k8sClient
+
+	specs, err := t.loadSpecs(templatesDir, manifestsDir)
+	if err != nil {
+		glog.Warningf("Failed to load template specs: %v", err)
+		return ErrInitTest
+	}
+	if len(specs) == 0 {
+		glog.Warningf("No template specs found for %s", describeSpecSource(templatesDir))
+		return ErrNoTemplateSpecs
+	}
+	t.specs = specs
 	return nil
 }
 
-// Run executes the smoketest for the `Template` and `TemplateInstance` controllers.
-// This will perform the following actions within the current namespace:
+func (t *Smoketest) loadSpecs(templatesDir string, manifestsDir string) ([]*TemplateSpec, error) {
+	if templatesDir != "" {
+		return LoadTemplateSpecs(templatesDir)
+	}
+	configMap, err := t.k8sInterface.CoreV1().ConfigMaps(t.namespace).Get(defaultSpecsConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %v", t.namespace, defaultSpecsConfigMap, err)
+	}
+	return LoadTemplateSpecsFromConfigMap(configMap, manifestsDir)
+}
+
+func describeSpecSource(templatesDir string) string {
+	if templatesDir != "" {
+		return templatesDir
+	}
+	return fmt.Sprintf("ConfigMap %s", defaultSpecsConfigMap)
+}
+
+// Run executes the smoketest for the `Template` and `TemplateInstance` controllers, once per loaded
+// TemplateSpec. This performs the following actions within the current namespace for each spec:
 //
-// 1. Create a parameterized `Template` with the following:
-//   a. a `ConfigMap` with simple key-value pairs
-//   b. a batch `Job` that executes a bash command
-// 2. Launch a `TemplateInstance` from the above template, with simple parameters configured via a `Secret`
-func (t *Smoketest) Run(keepObjects bool, timeout int) (float64, error) {
+// 1. Create the spec's `Template` from its manifest.
+// 2. Launch a `TemplateInstance` from that template, with the spec's parameters configured via a `Secret`.
+// 3. Validate the spec's expected labels and per-object field expectations.
+func (t *Smoketest) Run(opts RunOptions) ([]Result, error) {
+	if len(t.specs) == 0 {
+		return nil, ErrNoTemplateSpecs
+	}
+	results := make([]Result, 0, len(t.specs))
+	for _, spec := range t.specs {
+		results = append(results, t.runSpec(spec, opts))
+	}
+	return results, nil
+}
+
+func (t *Smoketest) runSpec(spec *TemplateSpec, opts RunOptions) (result Result) {
+	result.Template = spec.Name
+	start := time.Now()
+	defer func() {
+		result.TotalDuration = time.Now().Sub(start).Seconds()
+	}()
+
 	workspace := t.namespace
 	id := strconv.FormatInt(time.Now().Unix(), 10)
-	glog.V(1).Infof("Started running template smoketest %s", id)
-	defer glog.V(1).Infof("Completed template smoketest %s", id)
-	template, err := t.createTemplateCheck(workspace, id)
-	if !keepObjects {
+	glog.V(1).Infof("Started running template smoketest %s for template %s", id, spec.Name)
+	defer glog.V(1).Infof("Completed template smoketest %s for template %s", id, spec.Name)
+
+	runHooks(PhasePreCreate, HookContext{Smoketest: t, Namespace: workspace, Spec: spec, ID: id, Result: &result})
+
+	createTemplateStart := time.Now()
+	template, err := t.createTemplateCheck(workspace, id, spec)
+	result.PhaseDurations = append(result.PhaseDurations, PhaseDuration{Phase: "create_template", Duration: time.Now().Sub(createTemplateStart).Seconds()})
+	if !opts.KeepObjects {
 		defer t.deleteTemplate(workspace, template)
 	}
 	if err != nil {
-		glog.Warningf("Failed testing template: %v", err)
-		return 0, err
-	}
-	ti, secret, duration, err := t.launchTemplateInstanceCheck(workspace, template.Name, id, timeout)
-	if !keepObjects {
+		glog.Warningf("Failed testing template %s: %v", spec.Name, err)
+		result.Err = err
+		return result
+	}
+	ti, secret, launchDuration, readyDurations, err := t.launchTemplateInstanceCheck(workspace, template.Name, id, opts, spec, &result)
+	result.LaunchDuration = launchDuration
+	result.ReadyDurations = readyDurations
+	if !opts.KeepObjects {
 		defer t.deleteSecret(workspace, secret)
 	}
-	if !keepObjects {
-		defer t.deleteTemplateInstance(workspace, ti)
+	if !opts.KeepObjects {
+		defer func() {
+			teardownDuration, teardownErr := t.waitForDeletion(workspace, ti, opts.TeardownTimeout)
+			result.TeardownDuration = teardownDuration
+			result.PhaseDurations = append(result.PhaseDurations, PhaseDuration{Phase: "delete", Duration: teardownDuration})
+			if teardownErr != nil {
+				glog.Warningf("Failed tearing down template instance for %s: %v", spec.Name, teardownErr)
+				if result.Err == nil {
+					result.Err = teardownErr
+				}
+			}
+			runHooks(PhasePostDelete, HookContext{Smoketest: t, Namespace: workspace, Spec: spec, ID: id, Instance: ti, Result: &result})
+		}()
+	}
+	if err != nil {
+		glog.Warningf("Failed testing template instance for %s: %v", spec.Name, err)
+		result.Err = err
+		return result
 	}
+	glog.V(1).Infof("Successfully ran template smoketest %s for template %s", id, spec.Name)
+	return result
+}
+
+// loadTemplateManifest reads a Template manifest (JSON or YAML) from disk.
+func loadTemplateManifest(path string) (*templatev1.Template, error) {
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		glog.Warningf("Failed testing template instance: %v", err)
-		return duration, err
+		return nil, fmt.Errorf("failed to read template manifest %s: %v", path, err)
 	}
-	glog.V(1).Infof("Successfully ran template smoketest %s", id)
-	return duration, nil
+	template := &templatev1.Template{}
+	if err := yaml.Unmarshal(data, template); err != nil {
+		return nil, fmt.Errorf("failed to parse template manifest %s: %v", path, err)
+	}
+	return template, nil
 }
 
 // createTemplateCheck runs a smoke test to ensure that a `Template` can be created.
-func (t *Smoketest) createTemplateCheck(namespace string, id string) (*templatev1.Template, error) {
+func (t *Smoketest) createTemplateCheck(namespace string, id string, spec *TemplateSpec) (*templatev1.Template, error) {
 	glog.V(1).Info("Checking that a template can be created")
 	defer glog.V(1).Info("Completed template creation check")
-	templateName := fmt.Sprintf("smoketest-template-%s", id)
-	var testTemplate = &templatev1.Template{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: templateName,
-		},
-		Objects: []runtime.RawExtension{
-			runtime.RawExtension{
-				Raw: []byte(configMapJSON),
-			},
-			runtime.RawExtension{
-				Raw: []byte(jobJSON),
-			},
-		},
-		ObjectLabels: map[string]string{
-			"this":   "that",
-			"google": "kubernetes",
-			"redhat": "openshift",
-		},
-		Parameters: []templatev1.Parameter{
-			templatev1.Parameter{
-				Name:        "ID",
-				Description: "An identifier for all objects in the template instance.",
-				DisplayName: "ID",
-			},
-			templatev1.Parameter{
-				Name:        "SIMPLE_PARAM",
-				Description: "A simple parameter for a template.",
-				DisplayName: "Simple Parameter",
-			},
-			templatev1.Parameter{
-				Name:        "JSON_PARAM",
-				Description: "A JSON or YAML-formatted parameter.",
-				DisplayName: "JSON Parameter",
-			},
-		},
+	testTemplate, err := loadTemplateManifest(spec.ManifestPath)
+	if err != nil {
+		glog.Warningf("Failed to load manifest for template %s: %v", spec.Name, err)
+		return nil, ErrCreateTemplate
+	}
+	testTemplate.Name = fmt.Sprintf("smoketest-template-%s-%s", spec.Name, id)
+	if len(spec.ExpectedLabels) > 0 {
+		testTemplate.ObjectLabels = spec.ExpectedLabels
 	}
 	result, err := t.templateInterface.Templates(namespace).Create(testTemplate)
 	if err != nil {
@@ -263,25 +378,22 @@ func (t *Smoketest) deleteTemplateInstance(namespace string, instance *templatev
 	return nil
 }
 
-// getDummyTemplateParams returns dummy template parameters to use when creating a TemplateInstance from a Template.
-func (t *Smoketest) getDummyTemplateParams(id string) map[string]string {
-	return map[string]string{
-		"ID":           id,
-		"SIMPLE_PARAM": "test",
-		"JSON_PARAM":   "[ \"echo\", \"Hello world\" ]",
-	}
-}
-
-// launchTemplateInstanceCheck runs a smoke test to ensure a TemplateInstance can be launched from a Template.
-func (t *Smoketest) launchTemplateInstanceCheck(namespace string, templateName string, id string, timeoutInterval int) (*templatev1.TemplateInstance, *corev1.Secret, float64, error) {
+// launchTemplateInstanceCheck runs a smoke test to ensure a TemplateInstance can be launched from a
+// Template. Once the TemplateInstance itself reports ready, and if opts.Wait is set, it also waits
+// for every one of the TemplateInstance's child objects to become individually ready before
+// validating the spec's expectations.
+func (t *Smoketest) launchTemplateInstanceCheck(namespace string, templateName string, id string, opts RunOptions, spec *TemplateSpec, result *Result) (*templatev1.TemplateInstance, *corev1.Secret, float64, []KindDuration, error) {
 	glog.V(1).Info("Checking that an instance can be launched from a template")
 	defer glog.V(1).Info("Completed template instance launch check")
 	var duration float64
-	params := t.getDummyTemplateParams(id)
+	params := make(map[string]string, len(spec.Parameters))
+	for k, v := range spec.Parameters {
+		params[k] = substituteParams(v, map[string]string{"ID": id})
+	}
 	template, err := t.templateInterface.Templates(namespace).Get(templateName, metav1.GetOptions{})
 	if err != nil {
 		glog.Warningf("Failed to get template %s details: %v", templateName, err)
-		return nil, nil, duration, ErrCreateTemplate
+		return nil, nil, duration, nil, ErrCreateTemplate
 	}
 	glog.V(2).Infof("Fetched template %s\n", template.Name)
 	data := make(map[string][]byte)
@@ -294,13 +406,16 @@ func (t *Smoketest) launchTemplateInstanceCheck(namespace string, templateName s
 		},
 		Data: data,
 	}
+	createSecretStart := time.Now()
 	secretResult, err := t.k8sInterface.CoreV1().Secrets(namespace).Create(secret)
+	result.PhaseDurations = append(result.PhaseDurations, PhaseDuration{Phase: "create_secret", Duration: time.Now().Sub(createSecretStart).Seconds()})
 	if err != nil {
 		glog.Warningf("Failed to create secret %s for template instance: %v", secret.Name, err)
-		return nil, secretResult, duration, ErrCreateInstance
+		return nil, secretResult, duration, nil, ErrCreateInstance
 	}
 	glog.V(2).Infof("Created secret %s", secretResult.Name)
-	launchStart := time.Now()
+	runHooks(PhasePreInstantiate, HookContext{Smoketest: t, Namespace: namespace, Spec: spec, ID: id, Template: template, Params: params, Result: result})
+	createInstanceStart := time.Now()
 	ti, err := t.templateInterface.TemplateInstances(namespace).Create(&templatev1.TemplateInstance{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("%s-instance", templateName),
@@ -312,25 +427,27 @@ func (t *Smoketest) launchTemplateInstanceCheck(namespace string, templateName s
 			},
 		},
 	})
+	result.PhaseDurations = append(result.PhaseDurations, PhaseDuration{Phase: "create_instance", Duration: time.Now().Sub(createInstanceStart).Seconds()})
 	if err != nil {
 		glog.Warningf("Failed to create template instance: %v", err)
-		return nil, secretResult, duration, ErrCreateInstance
+		return nil, secretResult, duration, nil, ErrCreateInstance
 	}
 	glog.V(2).Infof("Created template instance %s", ti.Name)
+	launchStart := createInstanceStart
 	watcher, err := t.templateInterface.TemplateInstances(namespace).Watch(
 		metav1.SingleObject(ti.ObjectMeta),
 	)
 	if err != nil {
 		glog.Warningf("Failed to watch template instance %s: %v", ti.Name, err)
-		return ti, secretResult, duration, ErrUnknown
+		return ti, secretResult, duration, nil, ErrUnknown
 	}
 	glog.V(2).Infof("Waiting for template instance %s to be ready...", ti.Name)
-	timeout := time.After(time.Duration(timeoutInterval) * time.Second)
+	timeout := time.After(opts.Timeout)
 	for {
 		select {
 		case <-timeout:
 			duration = time.Now().Sub(launchStart).Seconds()
-			return ti, secretResult, duration, ErrLaunchInstanceTimeout
+			return ti, secretResult, duration, nil, ErrLaunchInstanceTimeout
 		case event := <-watcher.ResultChan():
 			switch event.Type {
 			case watch.Modified:
@@ -344,8 +461,20 @@ func (t *Smoketest) launchTemplateInstanceCheck(namespace string, templateName s
 						cond.Status == corev1.ConditionTrue {
 						watcher.Stop()
 						glog.V(2).Infof("Template instance %s is ready", ti.Name)
-						err = t.validateTemplateInstance(ti, template, params)
-						return ti, secretResult, duration, err
+						result.PhaseDurations = append(result.PhaseDurations, PhaseDuration{Phase: "wait_ready", Duration: duration})
+						var readyDurations []KindDuration
+						if opts.Wait {
+							readyDurations, err = t.waitForObjectsReady(namespace, ti.Status.Objects, opts.WaitTimeout)
+							if err != nil {
+								glog.Warningf("Child objects of template instance %s did not become ready: %v", ti.Name, err)
+								return ti, secretResult, duration, readyDurations, err
+							}
+						}
+						runHooks(PhasePostReady, HookContext{Smoketest: t, Namespace: namespace, Spec: spec, ID: id, Template: template, Instance: ti, Params: params, Result: result})
+						validateStart := time.Now()
+						err = t.validateTemplateInstance(ti, template, spec, params)
+						result.PhaseDurations = append(result.PhaseDurations, PhaseDuration{Phase: "validate", Duration: time.Now().Sub(validateStart).Seconds()})
+						return ti, secretResult, duration, readyDurations, err
 					}
 
 					// If the TemplateInstance contains a status condition
@@ -355,54 +484,114 @@ func (t *Smoketest) launchTemplateInstanceCheck(namespace string, templateName s
 						cond.Status == corev1.ConditionTrue {
 						watcher.Stop()
 						glog.Warningf("Failed to instantiate template instance %s", ti.Name)
-						return ti, secretResult, duration, ErrLaunchInstanceFailed
+						return ti, secretResult, duration, nil, ErrLaunchInstanceFailed
 					}
 				}
 
 			default:
 				duration = time.Now().Sub(launchStart).Seconds()
 				glog.Errorf("Unexpected event type %s watching template instance %s", event.Type, ti.Name)
-				return ti, secretResult, duration, ErrUnknown
+				return ti, secretResult, duration, nil, ErrUnknown
 			}
 		}
 	}
 }
 
-func (t *Smoketest) validateTemplateInstance(instance *templatev1.TemplateInstance, template *templatev1.Template, params map[string]string) error {
-	if !reflect.DeepEqual(template.Labels, instance.Labels) {
-		glog.Warningf("Labels for template %s {%s} and instance %s {%s} do not match", template.Name, template.Labels, instance.Name, instance.Labels)
-		return ErrInstanceInvalid
+// validateTemplateInstance checks that every child object the TemplateInstance created carries the
+// spec's expected labels, then evaluates every one of the spec's per-object field expectations.
+func (t *Smoketest) validateTemplateInstance(instance *templatev1.TemplateInstance, template *templatev1.Template, spec *TemplateSpec, params map[string]string) error {
+	if len(spec.ExpectedLabels) > 0 {
+		for _, obj := range instance.Status.Objects {
+			if err := t.checkObjectLabels(obj.Ref, spec.ExpectedLabels); err != nil {
+				return err
+			}
+		}
+	}
+	for _, exp := range spec.Expectations {
+		if err := t.checkObjectExpectation(exp, params); err != nil {
+			return err
+		}
 	}
-	configMapName := fmt.Sprintf("test-configmap-%s", params["ID"])
-	configMap, err := t.k8sInterface.CoreV1().ConfigMaps(t.namespace).Get(configMapName, metav1.GetOptions{})
+	glog.V(2).Infof("Validated template instance %s correctly launched from template %s", instance.Name, template.Name)
+	return nil
+}
+
+// checkObjectLabels fetches a single child object referenced by a TemplateInstance and asserts
+// that it carries every expected label, set on the created objects via the Template's ObjectLabels
+// rather than on the TemplateInstance itself.
+func (t *Smoketest) checkObjectLabels(ref corev1.ObjectReference, expected map[string]string) error {
+	labels, err := t.getChildLabels(t.namespace, ref.Kind, ref.Name)
 	if err != nil {
+		glog.Warningf("Failed to fetch %s %s to check expected labels: %v", ref.Kind, ref.Name, err)
 		return ErrLaunchInstanceFailed
 	}
-	expectedData := map[string]string{
-		"foo":         "bar",
-		"simpleParam": params["SIMPLE_PARAM"],
+	for key, value := range expected {
+		if labels[key] != value {
+			glog.Warningf("%s %s is missing expected label %s=%s (has %v)", ref.Kind, ref.Name, key, value, labels)
+			return ErrInstanceInvalid
+		}
 	}
-	if !reflect.DeepEqual(expectedData, configMap.Data) {
-		glog.Warningf("Data in config map %s does not match expected value %s", configMap.Data, expectedData)
-		return ErrInstanceInvalid
+	return nil
+}
+
+// checkObjectExpectation fetches the object an ObjectExpectation refers to and asserts that its
+// JSONPath-selected field matches the expected value, after parameter substitution.
+func (t *Smoketest) checkObjectExpectation(exp ObjectExpectation, params map[string]string) error {
+	name := substituteParams(exp.Name, params)
+	obj, err := t.getExpectationObject(exp.Kind, name)
+	if err != nil {
+		glog.Warningf("Failed to fetch %s %s for expectation check: %v", exp.Kind, name, err)
+		return ErrLaunchInstanceFailed
 	}
-	expectedArgs := make([]string, 0)
-	err = json.Unmarshal([]byte(params["JSON_PARAM"]), &expectedArgs)
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
-		glog.Errorf("Could not decode expected JSON: %v", err)
+		glog.Errorf("Failed to convert %s %s to an unstructured object: %v", exp.Kind, name, err)
 		return ErrUnknown
 	}
-	jobName := fmt.Sprintf("test-job-%s", params["ID"])
-	job, err := t.k8sInterface.BatchV1().Jobs(t.namespace).Get(jobName, metav1.GetOptions{})
+	actual, err := evalJSONPath(exp.FieldPath, unstructuredObj)
 	if err != nil {
-		glog.Warningf("Could not fetch details of job %s: %v", jobName, err)
-		return ErrLaunchInstanceFailed
+		glog.Warningf("Failed to evaluate field path %s on %s %s: %v", exp.FieldPath, exp.Kind, name, err)
+		return ErrInstanceInvalid
 	}
-	actualArgs := job.Spec.Template.Spec.Containers[0].Args
-	if !reflect.DeepEqual(expectedArgs, actualArgs) {
-		glog.Warningf("Arguments for instance job %s do not match expected value %s", actualArgs, expectedArgs)
+	expected := substituteParams(exp.ExpectedValue, params)
+	if actual != expected {
+		glog.Warningf("Field %s on %s %s is %q, expected %q", exp.FieldPath, exp.Kind, name, actual, expected)
 		return ErrInstanceInvalid
 	}
-	glog.V(2).Infof("Validated template instance %s correctly launched from template %s", instance.Name, template.Name)
 	return nil
 }
+
+// getExpectationObject fetches a single object of the given Kind and Name from the smoketest's namespace.
+func (t *Smoketest) getExpectationObject(kind string, name string) (interface{}, error) {
+	switch kind {
+	case "ConfigMap":
+		return t.k8sInterface.CoreV1().ConfigMaps(t.namespace).Get(name, metav1.GetOptions{})
+	case "Secret":
+		return t.k8sInterface.CoreV1().Secrets(t.namespace).Get(name, metav1.GetOptions{})
+	case "Service":
+		return t.k8sInterface.CoreV1().Services(t.namespace).Get(name, metav1.GetOptions{})
+	case "Job":
+		return t.k8sInterface.BatchV1().Jobs(t.namespace).Get(name, metav1.GetOptions{})
+	case "Pod":
+		return t.k8sInterface.CoreV1().Pods(t.namespace).Get(name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("unsupported expectation kind %q", kind)
+	}
+}
+
+// evalJSONPath evaluates a kubectl-style JSONPath expression (e.g. "{.data.foo}") against obj and
+// returns its result formatted as a string.
+func evalJSONPath(path string, obj interface{}) (string, error) {
+	jp := jsonpath.New("expectation")
+	if err := jp.Parse(path); err != nil {
+		return "", fmt.Errorf("invalid field path %s: %v", path, err)
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 || len(results[0]) == 0 {
+		return "", fmt.Errorf("field path %s produced no results", path)
+	}
+	return fmt.Sprintf("%v", results[0][0].Interface()), nil
+}
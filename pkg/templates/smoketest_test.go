@@ -8,12 +8,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var testSpec = &TemplateSpec{
+	Name:         "configmap",
+	ManifestPath: "testdata/configmap-template.yaml",
+	Parameters: map[string]string{
+		"ID":           "${ID}",
+		"SIMPLE_PARAM": "test",
+	},
+}
+
 func TestCreateTemplateSmoketest(t *testing.T) {
 	fakeClient := fakeV1.NewSimpleClientset().Template()
 	smoketest := &Smoketest{
 		templateInterface: fakeClient,
 	}
-	_, err := smoketest.createTemplateCheck("dummyNamespace", "testTemplate")
+	_, err := smoketest.createTemplateCheck("dummyNamespace", "testTemplate", testSpec)
 	if err != nil {
 		t.Errorf("Create template check failed: %s", err)
 	}
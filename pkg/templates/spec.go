@@ -0,0 +1,117 @@
+package templates
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// TemplateSpec describes a single Template to exercise as part of the smoketest, including the
+// parameter values to launch it with and the assertions that must hold once it is ready.
+type TemplateSpec struct {
+	// Name uniquely identifies this spec among the set of loaded specs, and is used to label metrics.
+	Name string `json:"name"`
+	// ManifestPath is the path to the Template manifest (JSON or YAML) to load. A relative path is
+	// resolved against the templates directory the spec itself was loaded from, or against the
+	// manifests directory when the spec was loaded from a ConfigMap.
+	ManifestPath string `json:"manifestPath"`
+	// Parameters are the parameter values to launch the TemplateInstance with. The literal "${ID}"
+	// may be used in any value to reference the smoketest run's generated identifier.
+	Parameters map[string]string `json:"parameters"`
+	// ExpectedLabels are the labels the Template applies to the objects it instantiates, via
+	// ObjectLabels, that each created child object must carry.
+	ExpectedLabels map[string]string `json:"expectedLabels,omitempty"`
+	// Expectations are field-level assertions to check against objects created by the template,
+	// once the TemplateInstance reports ready.
+	Expectations []ObjectExpectation `json:"expectations,omitempty"`
+}
+
+// ObjectExpectation asserts that a JSONPath field within a named, created object matches an expected value.
+// Both Name and ExpectedValue may reference "${PARAM}" parameter substitutions.
+type ObjectExpectation struct {
+	// Kind is the Kind of the object to fetch (e.g. ConfigMap, Job, Service).
+	Kind string `json:"kind"`
+	// Name is the name of the object, after parameter substitution.
+	Name string `json:"name"`
+	// FieldPath is a JSONPath expression, e.g. "{.data.foo}", evaluated against the fetched object.
+	FieldPath string `json:"fieldPath"`
+	// ExpectedValue is the value FieldPath must resolve to, after parameter substitution.
+	ExpectedValue string `json:"expectedValue"`
+}
+
+// LoadTemplateSpecs reads every *.yaml/*.yml/*.json file in dir and parses each into a TemplateSpec.
+// ManifestPath within a loaded spec is resolved relative to dir.
+func LoadTemplateSpecs(dir string) ([]*TemplateSpec, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory %s: %v", dir, err)
+	}
+	specs := make([]*TemplateSpec, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !isSpecFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template spec %s: %v", path, err)
+		}
+		spec, err := parseTemplateSpec(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template spec %s: %v", path, err)
+		}
+		if !filepath.IsAbs(spec.ManifestPath) {
+			spec.ManifestPath = filepath.Join(dir, spec.ManifestPath)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// LoadTemplateSpecsFromConfigMap parses one TemplateSpec per key of an in-cluster ConfigMap's data.
+// ManifestPath within a loaded spec is resolved relative to manifestDir.
+func LoadTemplateSpecsFromConfigMap(configMap *corev1.ConfigMap, manifestDir string) ([]*TemplateSpec, error) {
+	specs := make([]*TemplateSpec, 0, len(configMap.Data))
+	for key, data := range configMap.Data {
+		spec, err := parseTemplateSpec([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template spec %s: %v", key, err)
+		}
+		if !filepath.IsAbs(spec.ManifestPath) {
+			spec.ManifestPath = filepath.Join(manifestDir, spec.ManifestPath)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func parseTemplateSpec(data []byte) (*TemplateSpec, error) {
+	spec := &TemplateSpec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, fmt.Errorf("template spec is missing a name")
+	}
+	if spec.ManifestPath == "" {
+		return nil, fmt.Errorf("template spec %s is missing a manifestPath", spec.Name)
+	}
+	return spec, nil
+}
+
+func isSpecFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml" || ext == ".json"
+}
+
+// substituteParams replaces every "${NAME}" occurrence in value with params["NAME"].
+func substituteParams(value string, params map[string]string) string {
+	for k, v := range params {
+		value = strings.Replace(value, fmt.Sprintf("${%s}", k), v, -1)
+	}
+	return value
+}
@@ -0,0 +1,27 @@
+package templates
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTemplateSpecs(t *testing.T) {
+	specs, err := LoadTemplateSpecs("testdata/specs")
+	if err != nil {
+		t.Fatalf("Failed to load template specs: %s", err)
+	}
+	if len(specs) != 1 {
+		t.Fatalf("Expected 1 template spec, got %d", len(specs))
+	}
+	spec := specs[0]
+	if spec.Name != "configmap" {
+		t.Errorf("Expected spec name %q, got %q", "configmap", spec.Name)
+	}
+	expectedManifestPath := filepath.Join("testdata/specs", "../configmap-template.yaml")
+	if spec.ManifestPath != expectedManifestPath {
+		t.Errorf("Expected manifest path %q, got %q", expectedManifestPath, spec.ManifestPath)
+	}
+	if spec.Parameters["SIMPLE_PARAM"] != "test" {
+		t.Errorf("Expected SIMPLE_PARAM parameter %q, got %q", "test", spec.Parameters["SIMPLE_PARAM"])
+	}
+}
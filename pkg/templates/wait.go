@@ -0,0 +1,201 @@
+package templates
+
+import (
+	"time"
+
+	ocappsv1 "github.com/openshift/api/apps/v1"
+	templatev1 "github.com/openshift/api/template/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// readyPollInterval is how often a child object's readiness is re-checked while waiting.
+const readyPollInterval = 2 * time.Second
+
+// KindDuration records how long a single child object of a TemplateInstance took to become ready.
+type KindDuration struct {
+	// Kind is the object's Kind, e.g. "Deployment" or "Job".
+	Kind string
+	// Duration is how long the object took to become ready, in seconds.
+	Duration float64
+}
+
+// waitForObjectsReady polls every object a ready TemplateInstance created until each is
+// individually ready, Helm-style, rather than merely created. It returns a KindDuration for every
+// object that became ready, in the order given, stopping at the first object that times out or
+// errors.
+func (t *Smoketest) waitForObjectsReady(namespace string, objects []templatev1.TemplateInstanceObject, timeoutInterval int) ([]KindDuration, error) {
+	deadline := time.Now().Add(time.Duration(timeoutInterval) * time.Second)
+	durations := make([]KindDuration, 0, len(objects))
+	for _, obj := range objects {
+		start := time.Now()
+		if err := t.waitForObjectReady(namespace, obj.Ref, deadline); err != nil {
+			return durations, err
+		}
+		durations = append(durations, KindDuration{
+			Kind:     obj.Ref.Kind,
+			Duration: time.Now().Sub(start).Seconds(),
+		})
+	}
+	return durations, nil
+}
+
+// waitForObjectReady polls a single child object until isObjectReady reports true or the deadline passes.
+func (t *Smoketest) waitForObjectReady(namespace string, ref corev1.ObjectReference, deadline time.Time) error {
+	err := wait.PollImmediate(readyPollInterval, time.Until(deadline), func() (bool, error) {
+		return t.isObjectReady(namespace, ref)
+	})
+	if err == wait.ErrWaitTimeout {
+		return ErrObjectNotReadyTimeout
+	}
+	return err
+}
+
+// isObjectReady checks whether a single child object referenced by a TemplateInstance is ready,
+// using the same readiness invariants as `helm install --wait`. Kinds this smoketest does not
+// recognize are treated as ready immediately, without being fetched.
+func (t *Smoketest) isObjectReady(namespace string, ref corev1.ObjectReference) (bool, error) {
+	switch ref.Kind {
+	case "Deployment":
+		d, err := t.k8sInterface.AppsV1().Deployments(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return deploymentReady(d), nil
+	case "DeploymentConfig":
+		dc, err := t.appsInterface.DeploymentConfigs(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return deploymentConfigReady(dc), nil
+	case "Pod":
+		pod, err := t.k8sInterface.CoreV1().Pods(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return podReady(pod), nil
+	case "PersistentVolumeClaim":
+		pvc, err := t.k8sInterface.CoreV1().PersistentVolumeClaims(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return pvc.Status.Phase == corev1.ClaimBound, nil
+	case "Service":
+		svc, err := t.k8sInterface.CoreV1().Services(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		if svc.Spec.Type == corev1.ServiceTypeExternalName {
+			return true, nil
+		}
+		endpoints, err := t.k8sInterface.CoreV1().Endpoints(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return endpointsReady(endpoints), nil
+	case "Job":
+		job, err := t.k8sInterface.BatchV1().Jobs(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return jobReady(job), nil
+	case "DaemonSet":
+		ds, err := t.k8sInterface.AppsV1().DaemonSets(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		return ds.Status.DesiredNumberScheduled == ds.Status.NumberReady, nil
+	case "StatefulSet":
+		ss, err := t.k8sInterface.AppsV1().StatefulSets(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, ignoreNotFound(err)
+		}
+		replicas := int32(1)
+		if ss.Spec.Replicas != nil {
+			replicas = *ss.Spec.Replicas
+		}
+		return ss.Status.ReadyReplicas >= replicas, nil
+	default:
+		return true, nil
+	}
+}
+
+// deploymentReady mirrors the invariant Helm's `--wait` uses for Deployments: the rollout must
+// have updated every replica, and enough of them must be available to respect maxUnavailable.
+func deploymentReady(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	var maxUnavailable int32
+	if d.Spec.Strategy.RollingUpdate != nil && d.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+		value, _ := intstr.GetScaledValueFromIntOrPercent(d.Spec.Strategy.RollingUpdate.MaxUnavailable, int(replicas), true)
+		maxUnavailable = int32(value)
+	}
+	return d.Status.UpdatedReplicas >= replicas && d.Status.AvailableReplicas >= replicas-maxUnavailable
+}
+
+// deploymentConfigReady mirrors the Deployment invariant above, adapted to DeploymentConfig's
+// rollout status fields. Replica counts alone are not enough: a still-in-progress rollout can
+// briefly satisfy them with pods from the previous generation, so the DeploymentConfig's own
+// Progressing condition must also report the latest rollout complete.
+func deploymentConfigReady(dc *ocappsv1.DeploymentConfig) bool {
+	if dc.Status.ObservedGeneration < dc.Generation {
+		return false
+	}
+	if dc.Status.Replicas != dc.Spec.Replicas || dc.Status.ReadyReplicas != dc.Status.Replicas {
+		return false
+	}
+	return deploymentConfigRolloutComplete(dc)
+}
+
+// deploymentConfigRolloutComplete reports whether a DeploymentConfig's latest rollout has finished,
+// mirroring how `oc rollout status` inspects the Progressing condition of a Deployment/DeploymentConfig.
+func deploymentConfigRolloutComplete(dc *ocappsv1.DeploymentConfig) bool {
+	for _, cond := range dc.Status.Conditions {
+		if cond.Type == ocappsv1.DeploymentProgressing {
+			return cond.Status == corev1.ConditionTrue && cond.Reason == "NewReplicationControllerAvailable"
+		}
+	}
+	return false
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func endpointsReady(endpoints *corev1.Endpoints) bool {
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func jobReady(job *batchv1.Job) bool {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	return job.Status.Succeeded >= completions
+}
+
+// ignoreNotFound treats a NotFound error as "not ready yet" rather than a fatal error, since the
+// object may simply not have been created by the TemplateInstance controller yet.
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
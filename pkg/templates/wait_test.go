@@ -0,0 +1,232 @@
+package templates
+
+import (
+	"testing"
+
+	ocappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestDeploymentReady(t *testing.T) {
+	cases := []struct {
+		name string
+		d    *appsv1.Deployment
+		want bool
+	}{
+		{
+			name: "rolled out",
+			d: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 3},
+			},
+			want: true,
+		},
+		{
+			name: "still updating",
+			d: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 3},
+			},
+			want: false,
+		},
+		{
+			name: "not enough available",
+			d: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 0},
+			},
+			want: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := deploymentReady(c.d); got != c.want {
+				t.Errorf("deploymentReady() = %t, want %t", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDeploymentConfigReady(t *testing.T) {
+	rolledOut := []ocappsv1.DeploymentCondition{
+		{Type: ocappsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicationControllerAvailable"},
+	}
+	progressing := []ocappsv1.DeploymentCondition{
+		{Type: ocappsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicationControllerUpdated"},
+	}
+	cases := []struct {
+		name string
+		dc   *ocappsv1.DeploymentConfig
+		want bool
+	}{
+		{
+			name: "rolled out",
+			dc: &ocappsv1.DeploymentConfig{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       ocappsv1.DeploymentConfigSpec{Replicas: 2},
+				Status: ocappsv1.DeploymentConfigStatus{
+					ObservedGeneration: 2,
+					Replicas:           2,
+					ReadyReplicas:      2,
+					Conditions:         rolledOut,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "stale observed generation",
+			dc: &ocappsv1.DeploymentConfig{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       ocappsv1.DeploymentConfigSpec{Replicas: 2},
+				Status: ocappsv1.DeploymentConfigStatus{
+					ObservedGeneration: 1,
+					Replicas:           2,
+					ReadyReplicas:      2,
+					Conditions:         rolledOut,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "replica counts satisfied but rollout still in progress",
+			dc: &ocappsv1.DeploymentConfig{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       ocappsv1.DeploymentConfigSpec{Replicas: 2},
+				Status: ocappsv1.DeploymentConfigStatus{
+					ObservedGeneration: 2,
+					Replicas:           2,
+					ReadyReplicas:      2,
+					Conditions:         progressing,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "not enough ready replicas",
+			dc: &ocappsv1.DeploymentConfig{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       ocappsv1.DeploymentConfigSpec{Replicas: 2},
+				Status: ocappsv1.DeploymentConfigStatus{
+					ObservedGeneration: 2,
+					Replicas:           2,
+					ReadyReplicas:      1,
+					Conditions:         rolledOut,
+				},
+			},
+			want: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := deploymentConfigReady(c.dc); got != c.want {
+				t.Errorf("deploymentConfigReady() = %t, want %t", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPodReady(t *testing.T) {
+	cases := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{
+			name: "ready",
+			pod: &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			}}},
+			want: true,
+		},
+		{
+			name: "not ready",
+			pod: &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			}}},
+			want: false,
+		},
+		{
+			name: "no ready condition",
+			pod:  &corev1.Pod{},
+			want: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := podReady(c.pod); got != c.want {
+				t.Errorf("podReady() = %t, want %t", got, c.want)
+			}
+		})
+	}
+}
+
+func TestEndpointsReady(t *testing.T) {
+	cases := []struct {
+		name      string
+		endpoints *corev1.Endpoints
+		want      bool
+	}{
+		{
+			name: "has addresses",
+			endpoints: &corev1.Endpoints{Subsets: []corev1.EndpointSubset{
+				{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+			}},
+			want: true,
+		},
+		{
+			name: "no addresses",
+			endpoints: &corev1.Endpoints{Subsets: []corev1.EndpointSubset{
+				{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+			}},
+			want: false,
+		},
+		{
+			name:      "no subsets",
+			endpoints: &corev1.Endpoints{},
+			want:      false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := endpointsReady(c.endpoints); got != c.want {
+				t.Errorf("endpointsReady() = %t, want %t", got, c.want)
+			}
+		})
+	}
+}
+
+func TestJobReady(t *testing.T) {
+	cases := []struct {
+		name string
+		job  *batchv1.Job
+		want bool
+	}{
+		{
+			name: "default completions satisfied",
+			job:  &batchv1.Job{Status: batchv1.JobStatus{Succeeded: 1}},
+			want: true,
+		},
+		{
+			name: "explicit completions not yet satisfied",
+			job:  &batchv1.Job{Spec: batchv1.JobSpec{Completions: int32Ptr(3)}, Status: batchv1.JobStatus{Succeeded: 2}},
+			want: false,
+		},
+		{
+			name: "explicit completions satisfied",
+			job:  &batchv1.Job{Spec: batchv1.JobSpec{Completions: int32Ptr(3)}, Status: batchv1.JobStatus{Succeeded: 3}},
+			want: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := jobReady(c.job); got != c.want {
+				t.Errorf("jobReady() = %t, want %t", got, c.want)
+			}
+		})
+	}
+}